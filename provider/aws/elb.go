@@ -0,0 +1,243 @@
+package aws
+
+import (
+	"encoding/json"
+	"finala/config"
+	"finala/expression"
+	"finala/storage"
+	"finala/structs"
+	"time"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	log "github.com/sirupsen/logrus"
+)
+
+// ELBClientDescreptor is an interface defining the aws classic elb client
+type ELBClientDescreptor interface {
+	DescribeLoadBalancers(*elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error)
+	DescribeTags(*elb.DescribeTagsInput) (*elb.DescribeTagsOutput, error)
+}
+
+// ELBManager describe classic ELB struct
+type ELBManager struct {
+	client           ELBClientDescreptor
+	storage          storage.Storage
+	cloudWatchCLient *CloudwatchManager
+	pricingClient    *PricingManager
+	metrics          []config.MetricConfig
+	region           string
+
+	namespace          string
+	servicePricingCode string
+
+	// tagFilter is applied to every detected resource; see TagFilter.
+	tagFilter *TagFilter
+}
+
+// DetectedELB define the detected AWS classic ELB instances
+type DetectedELB struct {
+	Metric string
+	Region string
+	structs.BaseDetectedRaw
+}
+
+// TableName will set the table name to storage interface
+func (DetectedELB) TableName() string {
+	return "aws_elb"
+}
+
+// NewELBManager implements AWS GO SDK
+func NewELBManager(client ELBClientDescreptor, st storage.Storage, cloudWatchCLient *CloudwatchManager, pricing *PricingManager, metrics []config.MetricConfig, region string, tagFilter *TagFilter) *ELBManager {
+
+	st.AutoMigrate(&DetectedELB{})
+
+	return &ELBManager{
+		client:           client,
+		storage:          st,
+		cloudWatchCLient: cloudWatchCLient,
+		metrics:          metrics,
+		pricingClient:    pricing,
+		region:           region,
+		tagFilter:        tagFilter,
+
+		namespace:          "AWS/ELB",
+		servicePricingCode: "AmazonEC2",
+	}
+}
+
+// Detect checks whether a classic ELB instance is under utilization
+func (r *ELBManager) Detect() ([]DetectedELB, error) {
+	log.Info("Analyze ELB")
+	detectedELB := []DetectedELB{}
+
+	instances, err := r.DescribeLoadbalancers(nil, nil)
+	if err != nil {
+		return detectedELB, err
+	}
+
+	now := time.Now()
+
+	for _, instance := range instances {
+
+		log.WithField("name", *instance.LoadBalancerName).Info("check ELB")
+
+		var price float64
+		if r.pricingClient != nil {
+			price, _ = r.pricingClient.GetPrice(r.GetPricingFilterInput(), "")
+		}
+
+		for _, metric := range r.metrics {
+
+			log.WithFields(log.Fields{
+				"name":        *instance.LoadBalancerName,
+				"metric_name": metric.Description,
+			}).Debug("check metric")
+
+			period := int64(metric.Period.Seconds())
+
+			metricEndTime := now.Add(time.Duration(-metric.StartTime))
+
+			metricInput := cloudwatch.GetMetricStatisticsInput{
+				Namespace:  &r.namespace,
+				MetricName: &metric.Description,
+				Period:     &period,
+				StartTime:  &metricEndTime,
+				EndTime:    &now,
+				Dimensions: []*cloudwatch.Dimension{
+					&cloudwatch.Dimension{
+						Name:  awsClient.String("LoadBalancerName"),
+						Value: instance.LoadBalancerName,
+					},
+				},
+			}
+
+			metricResponse, err := r.cloudWatchCLient.GetMetric(&metricInput, metric)
+
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"name":        *instance.LoadBalancerName,
+					"metric_name": metric.Description,
+				}).Error("Could not get cloudwatch metric data")
+				continue
+			}
+
+			instanceCreateTime := *instance.CreatedTime
+			durationRunningTime := now.Sub(instanceCreateTime)
+			totalPrice := price * durationRunningTime.Hours()
+
+			expression, err := expression.BoolExpression(metricResponse, metric.Constraint.Value, metric.Constraint.Operator)
+			if err != nil {
+				continue
+			}
+
+			if expression {
+
+				decodedTags := []byte{}
+				tagPairs := map[string]string{}
+				tags, err := r.client.DescribeTags(&elb.DescribeTagsInput{
+					LoadBalancerNames: []*string{instance.LoadBalancerName},
+				})
+				if err == nil {
+					decodedTags, err = json.Marshal(&tags.TagDescriptions)
+					for _, description := range tags.TagDescriptions {
+						for _, tag := range description.Tags {
+							tagPairs[awsClient.StringValue(tag.Key)] = awsClient.StringValue(tag.Value)
+						}
+					}
+				}
+
+				if !r.tagFilter.allows(tagPairs) {
+					continue
+				}
+
+				log.WithFields(log.Fields{
+					"metric_name":         metric.Description,
+					"Constraint_operator": metric.Constraint.Operator,
+					"Constraint_Value":    metric.Constraint.Value,
+					"metric_response":     metricResponse,
+					"name":                *instance.LoadBalancerName,
+					"region":              r.region,
+				}).Info("LoadBalancer detected as unutilized resource")
+
+				elbResource := DetectedELB{
+					Region: r.region,
+					Metric: metric.Description,
+					BaseDetectedRaw: structs.BaseDetectedRaw{
+						ResourceID:      *instance.LoadBalancerName,
+						LaunchTime:      *instance.CreatedTime,
+						PricePerHour:    price,
+						PricePerMonth:   price * 720,
+						TotalSpendPrice: totalPrice,
+						Tags:            string(decodedTags),
+						CostGroup:       r.tagFilter.costGroup(tagPairs),
+					},
+				}
+				detectedELB = append(detectedELB, elbResource)
+				r.storage.Create(&elbResource)
+
+			}
+
+		}
+	}
+
+	return detectedELB, nil
+
+}
+
+// GetPricingFilterInput prepare document classic elb pricing filter
+func (r *ELBManager) GetPricingFilterInput() *pricing.GetProductsInput {
+
+	return &pricing.GetProductsInput{
+		ServiceCode: &r.servicePricingCode,
+		Filters: []*pricing.Filter{
+
+			&pricing.Filter{
+				Type:  awsClient.String("TERM_MATCH"),
+				Field: awsClient.String("usagetype"),
+				Value: awsClient.String("LoadBalancerUsage"),
+			},
+			&pricing.Filter{
+				Type:  awsClient.String("TERM_MATCH"),
+				Field: awsClient.String("TermType"),
+				Value: awsClient.String("OnDemand"),
+			},
+
+			&pricing.Filter{
+				Type:  awsClient.String("TERM_MATCH"),
+				Field: awsClient.String("group"),
+				Value: awsClient.String("ELB:Balancer"),
+			},
+		},
+	}
+}
+
+// DescribeLoadbalancers return list of classic loadbalancers
+func (r *ELBManager) DescribeLoadbalancers(marker *string, loadbalancers []*elb.LoadBalancerDescription) ([]*elb.LoadBalancerDescription, error) {
+
+	input := &elb.DescribeLoadBalancersInput{
+		Marker: marker,
+	}
+
+	resp, err := r.client.DescribeLoadBalancers(input)
+	if err != nil {
+		log.WithField("error", err).Error("could not describe elb instances")
+		return nil, err
+	}
+
+	if loadbalancers == nil {
+		loadbalancers = []*elb.LoadBalancerDescription{}
+	}
+
+	for _, lb := range resp.LoadBalancerDescriptions {
+		loadbalancers = append(loadbalancers, lb)
+	}
+
+	if resp.NextMarker != nil {
+		return r.DescribeLoadbalancers(resp.NextMarker, loadbalancers)
+	}
+
+	return loadbalancers, nil
+}
@@ -0,0 +1,88 @@
+package aws_test
+
+import (
+	"testing"
+
+	"finala/provider/aws"
+	"finala/testutils"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+)
+
+type mockELBClient struct {
+	pages [][]*elb.LoadBalancerDescription
+}
+
+func (m *mockELBClient) DescribeLoadBalancers(input *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
+	page := 0
+	if input.Marker != nil {
+		page = int(awsClient.StringValue(input.Marker)[0] - '0')
+	}
+
+	output := &elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: m.pages[page]}
+	if page+1 < len(m.pages) {
+		output.NextMarker = awsClient.String(string(rune('0' + page + 1)))
+	}
+
+	return output, nil
+}
+
+func (m *mockELBClient) DescribeTags(input *elb.DescribeTagsInput) (*elb.DescribeTagsOutput, error) {
+	return &elb.DescribeTagsOutput{}, nil
+}
+
+func TestELBManagerDescribeLoadbalancersPaginates(t *testing.T) {
+	client := &mockELBClient{
+		pages: [][]*elb.LoadBalancerDescription{
+			{{LoadBalancerName: awsClient.String("lb-0")}},
+			{{LoadBalancerName: awsClient.String("lb-1")}, {LoadBalancerName: awsClient.String("lb-2")}},
+		},
+	}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewELBManager(client, mockStorage, nil, nil, nil, "us-east-1", nil)
+
+	loadbalancers, err := manager.DescribeLoadbalancers(nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(loadbalancers) != 3 {
+		t.Fatalf("unexpected load balancer count, got %d expected %d", len(loadbalancers), 3)
+	}
+}
+
+func TestELBManagerDetectWithNilPricingClient(t *testing.T) {
+	client := &mockELBClient{
+		pages: [][]*elb.LoadBalancerDescription{
+			{{LoadBalancerName: awsClient.String("lb-0")}},
+		},
+	}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewELBManager(client, mockStorage, nil, nil, nil, "us-east-1", nil)
+
+	detected, err := manager.Detect()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(detected) != 0 {
+		t.Fatalf("expected no detections with no configured metrics, got %d", len(detected))
+	}
+}
+
+func TestELBManagerGetPricingFilterInput(t *testing.T) {
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewELBManager(&mockELBClient{}, mockStorage, nil, nil, nil, "us-east-1", nil)
+
+	filter := manager.GetPricingFilterInput()
+
+	if awsClient.StringValue(filter.ServiceCode) != "AmazonEC2" {
+		t.Fatalf("unexpected service code, got %s", awsClient.StringValue(filter.ServiceCode))
+	}
+
+	for _, f := range filter.Filters {
+		if awsClient.StringValue(f.Field) == "productFamily" {
+			t.Fatalf("classic ELB pricing filter should not constrain productFamily, found %s", awsClient.StringValue(f.Value))
+		}
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"finala/expression"
 	"finala/storage"
 	"finala/structs"
+	"fmt"
 	"regexp"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	awsClient "github.com/aws/aws-sdk-go/aws"
@@ -16,10 +19,24 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// defaultELBV2Concurrency bounds how many load balancers are analyzed in parallel
+// when the manager is constructed without an explicit concurrency value.
+const defaultELBV2Concurrency = 10
+
+// Metric names used when an ALB is flagged as unutilized from its listener/target
+// group inventory rather than from a CloudWatch metric threshold.
+const (
+	metricNoListeners     = "NoListeners"
+	metricNoHealthyTarget = "NoHealthyTargets"
+)
+
 // ELBV2ClientDescreptor is an interface defining the aws elbv2 client
 type ELBV2ClientDescreptor interface {
 	DescribeLoadBalancers(*elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error)
 	DescribeTags(*elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error)
+	DescribeListeners(*elbv2.DescribeListenersInput) (*elbv2.DescribeListenersOutput, error)
+	DescribeTargetGroups(*elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error)
+	DescribeTargetHealth(*elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error)
 }
 
 // ELBV2Manager describe TODO::appname ELB struct
@@ -33,6 +50,27 @@ type ELBV2Manager struct {
 
 	namespace          string
 	servicePricingCode string
+
+	concurrency int
+	priceCache  *requestCache
+	tagsCache   *requestCache
+
+	// metricBatcher, when set, routes CloudWatch metric lookups through
+	// GetMetricData batching instead of one GetMetricStatistics call per
+	// load balancer per metric.
+	metricBatcher      MetricQueryBatcher
+	metricQueryCounter uint64
+
+	// tagFilter is applied to every detected resource; see TagFilter.
+	tagFilter *TagFilter
+}
+
+// tagLookup bundles a resource's tags as a normalized key/value map (used for
+// filtering and cost-allocation grouping) with their JSON-encoded form (used
+// for storage), so a single DescribeTags call serves both purposes.
+type tagLookup struct {
+	pairs map[string]string
+	json  []byte
 }
 
 // DetectedELBV2 define the detected AWS ELB instances
@@ -47,10 +85,47 @@ func (DetectedELBV2) TableName() string {
 	return "aws_elbv2"
 }
 
+// DetectedELBV2Listener define a listener attached to an analyzed ALB
+type DetectedELBV2Listener struct {
+	LoadBalancerArn string
+	ListenerArn     string
+	Protocol        string
+	Port            int64
+	Region          string
+}
+
+// TableName will set the table name to storage interface
+func (DetectedELBV2Listener) TableName() string {
+	return "aws_elbv2_listener"
+}
+
+// DetectedELBV2TargetGroup define a target group attached to an analyzed ALB,
+// along with the healthy/unhealthy target counts observed at scan time
+type DetectedELBV2TargetGroup struct {
+	LoadBalancerArn string
+	TargetGroupArn  string
+	Protocol        string
+	Port            int64
+	HealthyCount    int64
+	UnhealthyCount  int64
+	Region          string
+}
+
+// TableName will set the table name to storage interface
+func (DetectedELBV2TargetGroup) TableName() string {
+	return "aws_elbv2_target_group"
+}
+
 // NewELBV2Manager implements AWS GO SDK
-func NewELBV2Manager(client ELBV2ClientDescreptor, st storage.Storage, cloudWatchCLient *CloudwatchManager, pricing *PricingManager, metrics []config.MetricConfig, region string) *ELBV2Manager {
+func NewELBV2Manager(client ELBV2ClientDescreptor, st storage.Storage, cloudWatchCLient *CloudwatchManager, pricing *PricingManager, metrics []config.MetricConfig, region string, concurrency int, tagFilter *TagFilter) *ELBV2Manager {
 
 	st.AutoMigrate(&DetectedELBV2{})
+	st.AutoMigrate(&DetectedELBV2Listener{})
+	st.AutoMigrate(&DetectedELBV2TargetGroup{})
+
+	if concurrency <= 0 {
+		concurrency = defaultELBV2Concurrency
+	}
 
 	return &ELBV2Manager{
 		client:           client,
@@ -62,14 +137,37 @@ func NewELBV2Manager(client ELBV2ClientDescreptor, st storage.Storage, cloudWatc
 
 		namespace:          "AWS/ApplicationELB",
 		servicePricingCode: "AmazonEC2",
+
+		concurrency: concurrency,
+		priceCache:  newRequestCache(),
+		tagsCache:   newRequestCache(),
+		tagFilter:   tagFilter,
 	}
 }
 
-// Detect check with ELBV2 instance is under utilization
+// SetMetricQueryBatcher switches the manager from one GetMetricStatistics call
+// per load balancer per metric to batched GetMetricData queries flushed once
+// per scan. ELBV2Manager is the only manager that uses a MetricQueryBatcher;
+// passing nil restores the per-metric GetMetricStatistics behavior.
+func (r *ELBV2Manager) SetMetricQueryBatcher(batcher MetricQueryBatcher) {
+	r.metricBatcher = batcher
+}
+
+// Detect check with ELBV2 instance is under utilization. Load balancers are
+// analyzed concurrently, bounded by the manager's configured concurrency, since
+// CloudWatch metric lookups dominate wall-clock time on accounts with hundreds
+// of load balancers.
 func (r *ELBV2Manager) Detect() ([]DetectedELBV2, error) {
 	log.Info("Analyze ELBV2")
 	detectedELBV2 := []DetectedELBV2{}
 
+	// Managers are long-lived and Detect runs repeatedly on a timer, so the
+	// price/tag caches are reset on every scan rather than once at construction
+	// time - otherwise a load balancer's tags (and tag-filter/CostGroup
+	// decisions derived from them) would be served from the first scan forever.
+	r.priceCache.reset()
+	r.tagsCache.reset()
+
 	instances, err := r.DescribeLoadbalancers(nil, nil)
 	if err != nil {
 		return detectedELBV2, err
@@ -77,101 +175,449 @@ func (r *ELBV2Manager) Detect() ([]DetectedELBV2, error) {
 
 	now := time.Now()
 
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, r.concurrency)
+	pending := []pendingMetricQuery{}
+
 	for _, instance := range instances {
 
-		log.WithField("name", *instance.LoadBalancerName).Info("check ELBV2")
+		if instance.Type != nil && *instance.Type != elbv2.LoadBalancerTypeEnumApplication {
+			continue
+		}
 
-		price, _ := r.pricingClient.GetPrice(r.GetPricingFilterInput(), "")
+		wg.Add(1)
+		sem <- struct{}{}
 
-		for _, metric := range r.metrics {
+		go func(instance *elbv2.LoadBalancer) {
+			defer wg.Done()
+			defer func() { <-sem }()
 
-			log.WithFields(log.Fields{
-				"name":        *instance.LoadBalancerName,
-				"metric_name": metric.Description,
-			}).Debug("check metric")
+			listeners, targetGroups := r.describeInventory(instance)
+			price := r.cachedPrice()
 
-			period := int64(metric.Period.Seconds())
+			mu.Lock()
+			for i := range listeners {
+				r.storage.Create(&listeners[i])
+			}
+			for i := range targetGroups {
+				r.storage.Create(&targetGroups[i])
+			}
+			if resource, ok := r.detectInventoryWaste(instance, now, price, listeners, targetGroups); ok {
+				detectedELBV2 = append(detectedELBV2, resource)
+				r.storage.Create(&detectedELBV2[len(detectedELBV2)-1])
+			}
+			mu.Unlock()
+
+			if r.metricBatcher == nil {
+				detected := r.detectMetricsSync(instance, now, price)
+				mu.Lock()
+				for i := range detected {
+					detectedELBV2 = append(detectedELBV2, detected[i])
+					r.storage.Create(&detectedELBV2[len(detectedELBV2)-1])
+				}
+				mu.Unlock()
+				return
+			}
 
-			metricEndTime := now.Add(time.Duration(-metric.StartTime))
+			checks := r.queueMetricChecks(instance, now, price)
 
-			regx, _ := regexp.Compile(".*loadbalancer/")
+			mu.Lock()
+			pending = append(pending, checks...)
+			mu.Unlock()
+		}(instance)
+	}
 
-			elbv2Name := regx.ReplaceAllString(*instance.LoadBalancerArn, "")
+	wg.Wait()
 
-			metricInput := cloudwatch.GetMetricStatisticsInput{
-				Namespace:  &r.namespace,
-				MetricName: &metric.Description,
-				Period:     &period,
-				StartTime:  &metricEndTime,
-				EndTime:    &now,
-				Dimensions: []*cloudwatch.Dimension{
-					&cloudwatch.Dimension{
-						Name:  awsClient.String("LoadBalancer"),
-						Value: &elbv2Name,
-					},
-				},
+	if r.metricBatcher != nil && len(pending) > 0 {
+		r.metricBatcher.Flush()
+
+		for _, check := range pending {
+			if resource, ok := r.evaluateMetricCheck(check); ok {
+				detectedELBV2 = append(detectedELBV2, resource)
+				r.storage.Create(&detectedELBV2[len(detectedELBV2)-1])
 			}
+		}
+	}
 
-			metricResponse, err := r.cloudWatchCLient.GetMetric(&metricInput, metric)
+	return detectedELBV2, nil
 
-			if err != nil {
-				log.WithError(err).WithFields(log.Fields{
-					"name":        *instance.LoadBalancerName,
-					"metric_name": metric.Description,
-				}).Error("Could not get cloudwatch metric data")
-				continue
-			}
+}
 
-			instanceCreateTime := *instance.CreatedTime
-			durationRunningTime := now.Sub(instanceCreateTime)
-			totalPrice := price * durationRunningTime.Hours()
+// describeInventory enumerates an ALB's listeners and target groups, along with
+// each target group's target health, and returns them as storage-ready rows.
+func (r *ELBV2Manager) describeInventory(instance *elbv2.LoadBalancer) ([]DetectedELBV2Listener, []DetectedELBV2TargetGroup) {
+	listenerRows := []DetectedELBV2Listener{}
+	targetGroupRows := []DetectedELBV2TargetGroup{}
 
-			expression, err := expression.BoolExpression(metricResponse, metric.Constraint.Value, metric.Constraint.Operator)
-			if err != nil {
-				continue
-			}
+	listeners, err := r.client.DescribeListeners(&elbv2.DescribeListenersInput{LoadBalancerArn: instance.LoadBalancerArn})
+	if err != nil {
+		log.WithError(err).WithField("name", *instance.LoadBalancerName).Error("could not describe listeners")
+	} else {
+		for _, listener := range listeners.Listeners {
+			listenerRows = append(listenerRows, DetectedELBV2Listener{
+				LoadBalancerArn: *instance.LoadBalancerArn,
+				ListenerArn:     *listener.ListenerArn,
+				Protocol:        *listener.Protocol,
+				Port:            *listener.Port,
+				Region:          r.region,
+			})
+		}
+	}
 
-			if expression {
-
-				log.WithFields(log.Fields{
-					"metric_name":         metric.Description,
-					"Constraint_operator": metric.Constraint.Operator,
-					"Constraint_Value":    metric.Constraint.Value,
-					"metric_response":     metricResponse,
-					"name":                *instance.LoadBalancerName,
-					"region":              r.region,
-				}).Info("LoadBalancer detected as unutilized resource")
-
-				decodedTags := []byte{}
-				tags, err := r.client.DescribeTags(&elbv2.DescribeTagsInput{
-					ResourceArns: []*string{instance.LoadBalancerArn},
-				})
-				if err == nil {
-					decodedTags, err = json.Marshal(&tags.TagDescriptions)
-				}
+	targetGroups, err := r.client.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{LoadBalancerArn: instance.LoadBalancerArn})
+	if err != nil {
+		log.WithError(err).WithField("name", *instance.LoadBalancerName).Error("could not describe target groups")
+	} else {
+		for _, targetGroup := range targetGroups.TargetGroups {
+			healthy, unhealthy := r.describeTargetHealth(targetGroup.TargetGroupArn)
+			targetGroupRows = append(targetGroupRows, DetectedELBV2TargetGroup{
+				LoadBalancerArn: *instance.LoadBalancerArn,
+				TargetGroupArn:  *targetGroup.TargetGroupArn,
+				Protocol:        *targetGroup.Protocol,
+				Port:            *targetGroup.Port,
+				HealthyCount:    healthy,
+				UnhealthyCount:  unhealthy,
+				Region:          r.region,
+			})
+		}
+	}
+
+	return listenerRows, targetGroupRows
+}
+
+// describeTargetHealth returns the healthy and unhealthy target counts for a
+// target group.
+func (r *ELBV2Manager) describeTargetHealth(targetGroupArn *string) (int64, int64) {
+	var healthy, unhealthy int64
+
+	health, err := r.client.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: targetGroupArn})
+	if err != nil {
+		log.WithError(err).WithField("target_group_arn", *targetGroupArn).Error("could not describe target health")
+		return 0, 0
+	}
+
+	for _, target := range health.TargetHealthDescriptions {
+		if target.TargetHealth == nil || target.TargetHealth.State == nil {
+			continue
+		}
+		if *target.TargetHealth.State == elbv2.TargetHealthStateEnumHealthy {
+			healthy++
+		} else {
+			unhealthy++
+		}
+	}
+
+	return healthy, unhealthy
+}
+
+// pendingMetricQuery tracks a load balancer/metric pair whose CloudWatch query
+// has been queued with the manager's MetricQueryBatcher but not yet evaluated,
+// because the batch it belongs to has not been flushed.
+type pendingMetricQuery struct {
+	instance *elbv2.LoadBalancer
+	metric   config.MetricConfig
+	now      time.Time
+	price    float64
+	result   <-chan MetricDataResult
+}
+
+// detectMetricsSync checks a single load balancer against every configured
+// metric via the manager's CloudwatchManager, one GetMetricStatistics call per
+// metric. This is the path used when no MetricQueryBatcher is configured.
+func (r *ELBV2Manager) detectMetricsSync(instance *elbv2.LoadBalancer, now time.Time, price float64) []DetectedELBV2 {
+	detected := []DetectedELBV2{}
+
+	regx, _ := regexp.Compile(".*loadbalancer/")
+	elbv2Name := regx.ReplaceAllString(*instance.LoadBalancerArn, "")
+
+	for _, metric := range r.metrics {
+
+		log.WithFields(log.Fields{
+			"name":        *instance.LoadBalancerName,
+			"metric_name": metric.Description,
+		}).Debug("check metric")
+
+		period := int64(metric.Period.Seconds())
+
+		metricEndTime := now.Add(time.Duration(-metric.StartTime))
+
+		metricInput := cloudwatch.GetMetricStatisticsInput{
+			Namespace:  &r.namespace,
+			MetricName: &metric.Description,
+			Period:     &period,
+			StartTime:  &metricEndTime,
+			EndTime:    &now,
+			Dimensions: []*cloudwatch.Dimension{
+				&cloudwatch.Dimension{
+					Name:  awsClient.String("LoadBalancer"),
+					Value: &elbv2Name,
+				},
+			},
+		}
+
+		metricResponse, err := r.cloudWatchCLient.GetMetric(&metricInput, metric)
+
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"name":        *instance.LoadBalancerName,
+				"metric_name": metric.Description,
+			}).Error("Could not get cloudwatch metric data")
+			continue
+		}
+
+		if resource, ok := r.buildMetricDetection(instance, metric, now, price, metricResponse); ok {
+			detected = append(detected, resource)
+		}
+	}
+
+	return detected
+}
 
-				elbv2 := DetectedELBV2{
-					Region: r.region,
-					Metric: metric.Description,
-					BaseDetectedRaw: structs.BaseDetectedRaw{
-						ResourceID:      *instance.LoadBalancerName,
-						LaunchTime:      *instance.CreatedTime,
-						PricePerHour:    price,
-						PricePerMonth:   price * 720,
-						TotalSpendPrice: totalPrice,
-						Tags:            string(decodedTags),
+// queueMetricChecks queues a GetMetricData query per configured metric with the
+// manager's MetricQueryBatcher instead of issuing a GetMetricStatistics call
+// immediately, and returns a pendingMetricQuery per metric to evaluate once the
+// batch has been flushed.
+func (r *ELBV2Manager) queueMetricChecks(instance *elbv2.LoadBalancer, now time.Time, price float64) []pendingMetricQuery {
+	checks := make([]pendingMetricQuery, 0, len(r.metrics))
+
+	regx, _ := regexp.Compile(".*loadbalancer/")
+	elbv2Name := regx.ReplaceAllString(*instance.LoadBalancerArn, "")
+
+	for _, metric := range r.metrics {
+
+		period := int64(metric.Period.Seconds())
+		metricStartTime := now.Add(time.Duration(-metric.StartTime))
+
+		query := &cloudwatch.MetricDataQuery{
+			MetricStat: &cloudwatch.MetricStat{
+				Metric: &cloudwatch.Metric{
+					Namespace:  &r.namespace,
+					MetricName: &metric.Description,
+					Dimensions: []*cloudwatch.Dimension{
+						&cloudwatch.Dimension{
+							Name:  awsClient.String("LoadBalancer"),
+							Value: &elbv2Name,
+						},
 					},
-				}
-				detectedELBV2 = append(detectedELBV2, elbv2)
-				r.storage.Create(&elbv2)
+				},
+				Period: &period,
+				Stat:   awsClient.String("Average"),
+			},
+		}
 
-			}
+		id := fmt.Sprintf("elbv2-%d", atomic.AddUint64(&r.metricQueryCounter, 1))
+
+		checks = append(checks, pendingMetricQuery{
+			instance: instance,
+			metric:   metric,
+			now:      now,
+			price:    price,
+			result:   r.metricBatcher.Add(id, query, metricStartTime, now),
+		})
+	}
+
+	return checks
+}
+
+// evaluateMetricCheck waits for a queued metric query's result and, if it
+// breaches the metric's constraint, builds the corresponding detected resource.
+func (r *ELBV2Manager) evaluateMetricCheck(check pendingMetricQuery) (DetectedELBV2, bool) {
+	result := <-check.result
+
+	if result.Err != nil {
+		log.WithError(result.Err).WithFields(log.Fields{
+			"name":        *check.instance.LoadBalancerName,
+			"metric_name": check.metric.Description,
+		}).Error("Could not get cloudwatch metric data")
+		return DetectedELBV2{}, false
+	}
+
+	if len(result.Values) == 0 {
+		return DetectedELBV2{}, false
+	}
+
+	var sum float64
+	for _, value := range result.Values {
+		sum += value
+	}
+	metricResponse := sum / float64(len(result.Values))
+
+	return r.buildMetricDetection(check.instance, check.metric, check.now, check.price, metricResponse)
+}
+
+// buildMetricDetection evaluates metric's constraint against metricResponse and,
+// if breached and the load balancer's tags pass the manager's tag filter,
+// builds the DetectedELBV2 row for it.
+func (r *ELBV2Manager) buildMetricDetection(instance *elbv2.LoadBalancer, metric config.MetricConfig, now time.Time, price float64, metricResponse float64) (DetectedELBV2, bool) {
+
+	expressionResult, err := expression.BoolExpression(metricResponse, metric.Constraint.Value, metric.Constraint.Operator)
+	if err != nil || !expressionResult {
+		return DetectedELBV2{}, false
+	}
+
+	tags, err := r.cachedTagLookup(instance.LoadBalancerArn)
+	if err != nil {
+		tags = tagLookup{}
+	}
+
+	if !r.tagFilter.allows(tags.pairs) {
+		return DetectedELBV2{}, false
+	}
+
+	log.WithFields(log.Fields{
+		"metric_name":         metric.Description,
+		"Constraint_operator": metric.Constraint.Operator,
+		"Constraint_Value":    metric.Constraint.Value,
+		"metric_response":     metricResponse,
+		"name":                *instance.LoadBalancerName,
+		"region":              r.region,
+	}).Info("LoadBalancer detected as unutilized resource")
+
+	instanceCreateTime := *instance.CreatedTime
+	durationRunningTime := now.Sub(instanceCreateTime)
+	totalPrice := price * durationRunningTime.Hours()
+
+	return DetectedELBV2{
+		Region: r.region,
+		Metric: metric.Description,
+		BaseDetectedRaw: structs.BaseDetectedRaw{
+			ResourceID:      *instance.LoadBalancerName,
+			LaunchTime:      *instance.CreatedTime,
+			PricePerHour:    price,
+			PricePerMonth:   price * 720,
+			TotalSpendPrice: totalPrice,
+			Tags:            string(tags.json),
+			CostGroup:       r.tagFilter.costGroup(tags.pairs),
+		},
+	}, true
+}
+
+// detectInventoryWaste flags a load balancer as unutilized when its inventory
+// shows it cannot be serving traffic: no listeners, or target groups with no
+// healthy targets. It returns ok=false when the load balancer's inventory gives
+// no reason to flag it.
+func (r *ELBV2Manager) detectInventoryWaste(instance *elbv2.LoadBalancer, now time.Time, price float64, listeners []DetectedELBV2Listener, targetGroups []DetectedELBV2TargetGroup) (DetectedELBV2, bool) {
+
+	metric := metricNoListeners
+	if len(listeners) > 0 {
+		if len(targetGroups) == 0 {
+			return DetectedELBV2{}, false
+		}
+
+		var healthyTargets int64
+		for _, targetGroup := range targetGroups {
+			healthyTargets += targetGroup.HealthyCount
+		}
 
+		if healthyTargets > 0 {
+			return DetectedELBV2{}, false
 		}
+
+		metric = metricNoHealthyTarget
 	}
 
-	return detectedELBV2, nil
+	tags, err := r.cachedTagLookup(instance.LoadBalancerArn)
+	if err != nil {
+		tags = tagLookup{}
+	}
+
+	if !r.tagFilter.allows(tags.pairs) {
+		return DetectedELBV2{}, false
+	}
+
+	log.WithFields(log.Fields{
+		"metric_name": metric,
+		"name":        *instance.LoadBalancerName,
+		"region":      r.region,
+	}).Info("LoadBalancer detected as unutilized resource")
+
+	instanceCreateTime := *instance.CreatedTime
+	durationRunningTime := now.Sub(instanceCreateTime)
+	totalPrice := price * durationRunningTime.Hours()
+
+	return DetectedELBV2{
+		Region: r.region,
+		Metric: metric,
+		BaseDetectedRaw: structs.BaseDetectedRaw{
+			ResourceID:      *instance.LoadBalancerName,
+			LaunchTime:      *instance.CreatedTime,
+			PricePerHour:    price,
+			PricePerMonth:   price * 720,
+			TotalSpendPrice: totalPrice,
+			Tags:            string(tags.json),
+			CostGroup:       r.tagFilter.costGroup(tags.pairs),
+		},
+	}, true
+}
+
+// cachedPrice returns the on-demand price for this manager's pricing filter,
+// memoizing the result per unique filter for the rest of the current scan
+// (Detect resets the cache at the start of each scan). Goroutines that miss
+// the cache at the same time each fetch the price.
+func (r *ELBV2Manager) cachedPrice() float64 {
+	if r.pricingClient == nil {
+		return 0
+	}
+
+	filterInput := r.GetPricingFilterInput()
+
+	key, keyErr := requestCacheKey(filterInput)
+	if keyErr == nil {
+		if cached, ok := r.priceCache.get(key); ok {
+			return cached.(float64)
+		}
+	}
+
+	price, _ := r.pricingClient.GetPrice(filterInput, "")
+
+	if keyErr == nil {
+		r.priceCache.set(key, price)
+	}
+
+	return price
+}
+
+// cachedTagLookup fetches the tags for arn, memoizing the result by the full
+// DescribeTags request so two different load balancers never collide under
+// the same cache entry. The result bundles both the raw JSON encoding stored
+// on the detected resource and a normalized key/value map used for tag
+// filtering and cost-allocation grouping.
+func (r *ELBV2Manager) cachedTagLookup(arn *string) (tagLookup, error) {
+	input := &elbv2.DescribeTagsInput{ResourceArns: []*string{arn}}
+
+	key, keyErr := requestCacheKey(input)
+	if keyErr == nil {
+		if cached, ok := r.tagsCache.get(key); ok {
+			return cached.(tagLookup), nil
+		}
+	}
+
+	tags, err := r.client.DescribeTags(input)
+	if err != nil {
+		return tagLookup{}, err
+	}
+
+	pairs := map[string]string{}
+	for _, description := range tags.TagDescriptions {
+		for _, tag := range description.Tags {
+			pairs[awsClient.StringValue(tag.Key)] = awsClient.StringValue(tag.Value)
+		}
+	}
+
+	decoded, err := json.Marshal(&tags.TagDescriptions)
+	if err != nil {
+		return tagLookup{}, err
+	}
+
+	lookup := tagLookup{pairs: pairs, json: decoded}
+
+	if keyErr == nil {
+		r.tagsCache.set(key, lookup)
+	}
 
+	return lookup, nil
 }
 
 // GetPricingFilterInput prepare document elb pricing filter
@@ -228,7 +674,7 @@ func (r *ELBV2Manager) DescribeLoadbalancers(marker *string, loadbalancers []*el
 	}
 
 	if resp.NextMarker != nil {
-		r.DescribeLoadbalancers(resp.NextMarker, loadbalancers)
+		return r.DescribeLoadbalancers(resp.NextMarker, loadbalancers)
 	}
 
 	return loadbalancers, nil
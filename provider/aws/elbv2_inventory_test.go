@@ -0,0 +1,118 @@
+package aws_test
+
+import (
+	"testing"
+	"time"
+
+	"finala/provider/aws"
+	"finala/testutils"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// inventoryELBV2Client lets each test configure the listener/target-group/
+// target-health inventory returned for the single load balancer under test.
+type inventoryELBV2Client struct {
+	listeners     []*elbv2.Listener
+	targetGroups  []*elbv2.TargetGroup
+	targetHealths []*elbv2.TargetHealthDescription
+}
+
+func (c *inventoryELBV2Client) DescribeLoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	createdTime := time.Now().Add(-24 * time.Hour)
+	return &elbv2.DescribeLoadBalancersOutput{
+		LoadBalancers: []*elbv2.LoadBalancer{
+			{
+				LoadBalancerName: awsClient.String("alb-0"),
+				LoadBalancerArn:  awsClient.String("arn:aws:elasticloadbalancing:us-east-1:1234:loadbalancer/app/alb-0/abc"),
+				Type:             awsClient.String(elbv2.LoadBalancerTypeEnumApplication),
+				CreatedTime:      &createdTime,
+			},
+		},
+	}, nil
+}
+
+func (c *inventoryELBV2Client) DescribeTags(input *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error) {
+	return &elbv2.DescribeTagsOutput{}, nil
+}
+
+func (c *inventoryELBV2Client) DescribeListeners(input *elbv2.DescribeListenersInput) (*elbv2.DescribeListenersOutput, error) {
+	return &elbv2.DescribeListenersOutput{Listeners: c.listeners}, nil
+}
+
+func (c *inventoryELBV2Client) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	return &elbv2.DescribeTargetGroupsOutput{TargetGroups: c.targetGroups}, nil
+}
+
+func (c *inventoryELBV2Client) DescribeTargetHealth(input *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	return &elbv2.DescribeTargetHealthOutput{TargetHealthDescriptions: c.targetHealths}, nil
+}
+
+func healthDescription(state string) *elbv2.TargetHealthDescription {
+	return &elbv2.TargetHealthDescription{
+		TargetHealth: &elbv2.TargetHealth{State: awsClient.String(state)},
+	}
+}
+
+func TestELBV2ManagerDetectInventoryWaste(t *testing.T) {
+	cases := []struct {
+		name         string
+		listeners    []*elbv2.Listener
+		targetGroups []*elbv2.TargetGroup
+		healths      []*elbv2.TargetHealthDescription
+		wantFlagged  bool
+		wantMetric   string
+	}{
+		{
+			name:        "no listeners is flagged",
+			wantFlagged: true,
+			wantMetric:  "NoListeners",
+		},
+		{
+			name:      "listeners with no target groups is not flagged",
+			listeners: []*elbv2.Listener{{ListenerArn: awsClient.String("l-0"), Protocol: awsClient.String("HTTP"), Port: awsClient.Int64(80)}},
+		},
+		{
+			name:         "listeners with only unhealthy targets is flagged",
+			listeners:    []*elbv2.Listener{{ListenerArn: awsClient.String("l-0"), Protocol: awsClient.String("HTTP"), Port: awsClient.Int64(80)}},
+			targetGroups: []*elbv2.TargetGroup{{TargetGroupArn: awsClient.String("tg-0"), Protocol: awsClient.String("HTTP"), Port: awsClient.Int64(80)}},
+			healths:      []*elbv2.TargetHealthDescription{healthDescription(elbv2.TargetHealthStateEnumUnhealthy)},
+			wantFlagged:  true,
+			wantMetric:   "NoHealthyTargets",
+		},
+		{
+			name:         "listeners with a healthy target is not flagged",
+			listeners:    []*elbv2.Listener{{ListenerArn: awsClient.String("l-0"), Protocol: awsClient.String("HTTP"), Port: awsClient.Int64(80)}},
+			targetGroups: []*elbv2.TargetGroup{{TargetGroupArn: awsClient.String("tg-0"), Protocol: awsClient.String("HTTP"), Port: awsClient.Int64(80)}},
+			healths:      []*elbv2.TargetHealthDescription{healthDescription(elbv2.TargetHealthStateEnumHealthy)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &inventoryELBV2Client{
+				listeners:     tc.listeners,
+				targetGroups:  tc.targetGroups,
+				targetHealths: tc.healths,
+			}
+			mockStorage := testutils.NewMockStorage()
+			manager := aws.NewELBV2Manager(client, mockStorage, nil, nil, nil, "us-east-1", 1, nil)
+
+			detected, err := manager.Detect()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if tc.wantFlagged && len(detected) != 1 {
+				t.Fatalf("expected load balancer to be flagged, got %d detections", len(detected))
+			}
+			if !tc.wantFlagged && len(detected) != 0 {
+				t.Fatalf("expected load balancer not to be flagged, got %d detections", len(detected))
+			}
+			if tc.wantFlagged && detected[0].Metric != tc.wantMetric {
+				t.Fatalf("unexpected metric, got %s expected %s", detected[0].Metric, tc.wantMetric)
+			}
+		})
+	}
+}
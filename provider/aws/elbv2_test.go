@@ -0,0 +1,125 @@
+package aws_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"finala/provider/aws"
+	"finala/testutils"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+type benchELBV2Client struct {
+	loadBalancers []*elbv2.LoadBalancer
+}
+
+func (b *benchELBV2Client) DescribeLoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	return &elbv2.DescribeLoadBalancersOutput{LoadBalancers: b.loadBalancers}, nil
+}
+
+func (b *benchELBV2Client) DescribeTags(input *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error) {
+	return &elbv2.DescribeTagsOutput{}, nil
+}
+
+func (b *benchELBV2Client) DescribeListeners(input *elbv2.DescribeListenersInput) (*elbv2.DescribeListenersOutput, error) {
+	return &elbv2.DescribeListenersOutput{
+		Listeners: []*elbv2.Listener{
+			{
+				ListenerArn: awsClient.String(*input.LoadBalancerArn + "/listener"),
+				Protocol:    awsClient.String("HTTP"),
+				Port:        awsClient.Int64(80),
+			},
+		},
+	}, nil
+}
+
+func (b *benchELBV2Client) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	return &elbv2.DescribeTargetGroupsOutput{
+		TargetGroups: []*elbv2.TargetGroup{
+			{
+				TargetGroupArn: awsClient.String(*input.LoadBalancerArn + "/tg"),
+				Protocol:       awsClient.String("HTTP"),
+				Port:           awsClient.Int64(80),
+			},
+		},
+	}, nil
+}
+
+func (b *benchELBV2Client) DescribeTargetHealth(input *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	return &elbv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+			{
+				TargetHealth: &elbv2.TargetHealth{State: awsClient.String(elbv2.TargetHealthStateEnumHealthy)},
+			},
+		},
+	}, nil
+}
+
+func manyLoadBalancers(n int) []*elbv2.LoadBalancer {
+	createdTime := time.Now().Add(-24 * time.Hour)
+	loadBalancers := make([]*elbv2.LoadBalancer, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("lb-%d", i)
+		loadBalancers[i] = &elbv2.LoadBalancer{
+			LoadBalancerName: awsClient.String(name),
+			LoadBalancerArn:  awsClient.String("arn:aws:elasticloadbalancing:us-east-1:1234:loadbalancer/app/" + name + "/abc"),
+			Type:             awsClient.String(elbv2.LoadBalancerTypeEnumApplication),
+			CreatedTime:      &createdTime,
+		}
+	}
+	return loadBalancers
+}
+
+// BenchmarkELBV2ManagerDescribeLoadbalancers measures DescribeLoadbalancers
+// throughput for an account with hundreds of load balancers, all returned in a
+// single page.
+func BenchmarkELBV2ManagerDescribeLoadbalancers(b *testing.B) {
+	client := &benchELBV2Client{loadBalancers: manyLoadBalancers(500)}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewELBV2Manager(client, mockStorage, nil, nil, nil, "us-east-1", 10, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := manager.DescribeLoadbalancers(nil, nil); err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+// BenchmarkELBV2ManagerDetect measures end-to-end scan throughput for accounts
+// with hundreds of load balancers, driving the concurrent worker pool and the
+// per-load-balancer inventory lookups. With no pricing client and no flagged
+// load balancers, the price and tag caches are reset but never read.
+func BenchmarkELBV2ManagerDetect(b *testing.B) {
+	client := &benchELBV2Client{loadBalancers: manyLoadBalancers(500)}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewELBV2Manager(client, mockStorage, nil, nil, nil, "us-east-1", 10, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := manager.Detect(); err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+// TestELBV2ManagerDetectConcurrency drives Detect() with many healthy load
+// balancers through the concurrent worker pool; run with -race to catch data
+// races in the shared detectedELBV2 slice.
+func TestELBV2ManagerDetectConcurrency(t *testing.T) {
+	client := &benchELBV2Client{loadBalancers: manyLoadBalancers(50)}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewELBV2Manager(client, mockStorage, nil, nil, nil, "us-east-1", 8, nil)
+
+	detected, err := manager.Detect()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(detected) != 0 {
+		t.Fatalf("expected no load balancers to be flagged as unutilized, got %d", len(detected))
+	}
+}
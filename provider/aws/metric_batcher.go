@@ -0,0 +1,184 @@
+package aws
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// maxMetricDataQueriesPerBatch mirrors the CloudWatch GetMetricData limit on the
+// number of MetricDataQuery entries allowed in a single request.
+const maxMetricDataQueriesPerBatch = 500
+
+// MetricDataResult is the outcome of a single queued MetricDataQuery, delivered
+// once the batch containing it has been flushed.
+type MetricDataResult struct {
+	Values []float64
+	Err    error
+}
+
+// MetricDataGetter is the subset of the CloudWatch client needed to batch metric
+// queries via GetMetricData.
+type MetricDataGetter interface {
+	GetMetricData(*cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// MetricQueryBatcher accumulates MetricDataQuery entries across every resource
+// scanned in a region and flushes them in batches bounded by the CloudWatch
+// GetMetricData query limit, instead of issuing one GetMetricStatistics call per
+// resource per metric. Queries are grouped by their [startTime, endTime] window,
+// so metrics configured with different lookback periods are never evaluated
+// against the wrong time range.
+type MetricQueryBatcher interface {
+	// Add queues query under id for the [startTime, endTime] window and returns
+	// a channel that receives its result once the batch containing it has been
+	// flushed. id must be unique across all outstanding queries on this batcher.
+	Add(id string, query *cloudwatch.MetricDataQuery, startTime, endTime time.Time) <-chan MetricDataResult
+	// Flush sends any queries still pending, regardless of batch size.
+	Flush()
+}
+
+// metricQueryWindow identifies the distinct [startTime, endTime] CloudWatch
+// lookback window a batch of queries was queued for.
+type metricQueryWindow struct {
+	startTime time.Time
+	endTime   time.Time
+}
+
+// metricQueryBatch accumulates the queries and result channels queued for a
+// single metricQueryWindow until it is flushed.
+type metricQueryBatch struct {
+	queries []*cloudwatch.MetricDataQuery
+	pending map[string]chan MetricDataResult
+}
+
+// cloudWatchMetricQueryBatcher is the default MetricQueryBatcher implementation.
+type cloudWatchMetricQueryBatcher struct {
+	client MetricDataGetter
+
+	mu      sync.Mutex
+	batches map[metricQueryWindow]*metricQueryBatch
+}
+
+// NewMetricQueryBatcher returns a MetricQueryBatcher that issues GetMetricData
+// calls through client, flushing a window's batch automatically once
+// maxMetricDataQueriesPerBatch queries have been queued for it.
+func NewMetricQueryBatcher(client MetricDataGetter) MetricQueryBatcher {
+	return &cloudWatchMetricQueryBatcher{
+		client:  client,
+		batches: map[metricQueryWindow]*metricQueryBatch{},
+	}
+}
+
+// Add queues query under id for the given window, flushing that window's batch
+// immediately if it is now full. The batch is capped and handed off for
+// flushing atomically with the append, so concurrent Add calls for the same
+// window can never grow a batch past maxMetricDataQueriesPerBatch before it is
+// sent - CloudWatch rejects the whole GetMetricData call if it does.
+func (b *cloudWatchMetricQueryBatcher) Add(id string, query *cloudwatch.MetricDataQuery, startTime, endTime time.Time) <-chan MetricDataResult {
+	result := make(chan MetricDataResult, 1)
+
+	query.Id = awsClient.String(id)
+	window := metricQueryWindow{startTime: startTime, endTime: endTime}
+
+	var full *metricQueryBatch
+
+	b.mu.Lock()
+	batch, ok := b.batches[window]
+	if !ok {
+		batch = &metricQueryBatch{pending: map[string]chan MetricDataResult{}}
+		b.batches[window] = batch
+	}
+	batch.queries = append(batch.queries, query)
+	batch.pending[id] = result
+	if len(batch.queries) >= maxMetricDataQueriesPerBatch {
+		full = batch
+		delete(b.batches, window)
+	}
+	b.mu.Unlock()
+
+	if full != nil {
+		b.sendBatch(window, full)
+	}
+
+	return result
+}
+
+// Flush sends every window's pending queries as one GetMetricData call per
+// window, regardless of batch size.
+func (b *cloudWatchMetricQueryBatcher) Flush() {
+	b.mu.Lock()
+	windows := make([]metricQueryWindow, 0, len(b.batches))
+	for window := range b.batches {
+		windows = append(windows, window)
+	}
+	b.mu.Unlock()
+
+	for _, window := range windows {
+		b.flushWindow(window)
+	}
+}
+
+// flushWindow pops window's current batch, if any, and sends it.
+func (b *cloudWatchMetricQueryBatcher) flushWindow(window metricQueryWindow) {
+	b.mu.Lock()
+	batch, ok := b.batches[window]
+	if !ok || len(batch.queries) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.batches, window)
+	b.mu.Unlock()
+
+	b.sendBatch(window, batch)
+}
+
+// sendBatch issues a single GetMetricData call for batch's queries against
+// window and delivers each result to its caller's channel. batch must already
+// have been removed from b.batches by the caller.
+func (b *cloudWatchMetricQueryBatcher) sendBatch(window metricQueryWindow, batch *metricQueryBatch) {
+	queries := batch.queries
+	pending := batch.pending
+
+	resp, err := b.client.GetMetricData(&cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         &window.startTime,
+		EndTime:           &window.endTime,
+	})
+
+	if err != nil {
+		for _, ch := range pending {
+			ch <- MetricDataResult{Err: err}
+			close(ch)
+		}
+		return
+	}
+
+	delivered := map[string]bool{}
+	for _, result := range resp.MetricDataResults {
+		ch, ok := pending[awsClient.StringValue(result.Id)]
+		if !ok {
+			continue
+		}
+
+		values := make([]float64, len(result.Values))
+		for i, v := range result.Values {
+			values[i] = awsClient.Float64Value(v)
+		}
+
+		ch <- MetricDataResult{Values: values}
+		close(ch)
+		delivered[awsClient.StringValue(result.Id)] = true
+	}
+
+	for id, ch := range pending {
+		if delivered[id] {
+			continue
+		}
+		ch <- MetricDataResult{Err: fmt.Errorf("no GetMetricData result returned for query %q", id)}
+		close(ch)
+	}
+}
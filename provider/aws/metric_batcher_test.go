@@ -0,0 +1,217 @@
+package aws_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"finala/provider/aws"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+type benchMetricDataClient struct{}
+
+func (c *benchMetricDataClient) GetMetricData(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	results := make([]*cloudwatch.MetricDataResult, len(input.MetricDataQueries))
+	for i, query := range input.MetricDataQueries {
+		results[i] = &cloudwatch.MetricDataResult{
+			Id:     query.Id,
+			Values: []*float64{awsClient.Float64(1.0)},
+		}
+	}
+	return &cloudwatch.GetMetricDataOutput{MetricDataResults: results}, nil
+}
+
+// mockMetricDataClient records every GetMetricData call it receives and lets
+// tests script an error or drop individual Ids from the response. Safe for
+// concurrent use, since a batcher can flush more than one window's batch at
+// once.
+type mockMetricDataClient struct {
+	mu      sync.Mutex
+	calls   []*cloudwatch.GetMetricDataInput
+	err     error
+	skipIds map[string]bool
+}
+
+func (c *mockMetricDataClient) GetMetricData(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	c.mu.Lock()
+	c.calls = append(c.calls, input)
+	c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	results := make([]*cloudwatch.MetricDataResult, 0, len(input.MetricDataQueries))
+	for _, query := range input.MetricDataQueries {
+		if c.skipIds[awsClient.StringValue(query.Id)] {
+			continue
+		}
+		results = append(results, &cloudwatch.MetricDataResult{
+			Id:     query.Id,
+			Values: []*float64{awsClient.Float64(2.0), awsClient.Float64(4.0)},
+		})
+	}
+	return &cloudwatch.GetMetricDataOutput{MetricDataResults: results}, nil
+}
+
+func testQuery() *cloudwatch.MetricDataQuery {
+	return &cloudwatch.MetricDataQuery{
+		MetricStat: &cloudwatch.MetricStat{
+			Metric: &cloudwatch.Metric{
+				Namespace:  awsClient.String("AWS/ApplicationELB"),
+				MetricName: awsClient.String("RequestCount"),
+			},
+			Period: awsClient.Int64(300),
+			Stat:   awsClient.String("Average"),
+		},
+	}
+}
+
+func TestMetricQueryBatcherDeliversValues(t *testing.T) {
+	client := &mockMetricDataClient{}
+	batcher := aws.NewMetricQueryBatcher(client)
+	now := time.Now()
+
+	result := batcher.Add("q1", testQuery(), now.Add(-time.Hour), now)
+	batcher.Flush()
+
+	got := <-result
+	if got.Err != nil {
+		t.Fatalf("unexpected error: %s", got.Err)
+	}
+	if len(got.Values) != 2 || got.Values[0] != 2.0 || got.Values[1] != 4.0 {
+		t.Fatalf("unexpected values: %v", got.Values)
+	}
+}
+
+func TestMetricQueryBatcherPropagatesClientError(t *testing.T) {
+	client := &mockMetricDataClient{err: errors.New("throttled")}
+	batcher := aws.NewMetricQueryBatcher(client)
+	now := time.Now()
+
+	result := batcher.Add("q1", testQuery(), now.Add(-time.Hour), now)
+	batcher.Flush()
+
+	got := <-result
+	if got.Err == nil {
+		t.Fatalf("expected error to propagate from GetMetricData")
+	}
+}
+
+func TestMetricQueryBatcherMissingResultIsAnError(t *testing.T) {
+	client := &mockMetricDataClient{skipIds: map[string]bool{"q1": true}}
+	batcher := aws.NewMetricQueryBatcher(client)
+	now := time.Now()
+
+	result := batcher.Add("q1", testQuery(), now.Add(-time.Hour), now)
+	batcher.Flush()
+
+	got := <-result
+	if got.Err == nil {
+		t.Fatalf("expected an error when GetMetricData omits a queued query's Id")
+	}
+}
+
+func TestMetricQueryBatcherFlushGroupsByWindow(t *testing.T) {
+	client := &mockMetricDataClient{}
+	batcher := aws.NewMetricQueryBatcher(client)
+	now := time.Now()
+
+	shortWindowResult := batcher.Add("short", testQuery(), now.Add(-5*time.Minute), now)
+	longWindowResult := batcher.Add("long", testQuery(), now.Add(-time.Hour), now)
+	batcher.Flush()
+
+	<-shortWindowResult
+	<-longWindowResult
+
+	if len(client.calls) != 2 {
+		t.Fatalf("expected one GetMetricData call per distinct window, got %d", len(client.calls))
+	}
+
+	for _, call := range client.calls {
+		if len(call.MetricDataQueries) != 1 {
+			t.Fatalf("expected each windowed call to carry only its own query, got %d queries", len(call.MetricDataQueries))
+		}
+	}
+}
+
+// TestMetricQueryBatcherConcurrentAddNeverExceedsCloudWatchLimit drives Add
+// concurrently from many goroutines for the same window - mirroring
+// ELBV2Manager.Detect(), where goroutines for load balancers sharing a metric
+// lookback window call Add in parallel - and asserts no resulting
+// GetMetricData call ever carries more than CloudWatch's 500-query limit.
+func TestMetricQueryBatcherConcurrentAddNeverExceedsCloudWatchLimit(t *testing.T) {
+	const (
+		maxQueriesPerBatch = 500
+		queryCount         = 1300
+	)
+
+	client := &mockMetricDataClient{}
+	batcher := aws.NewMetricQueryBatcher(client)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	results := make([]<-chan aws.MetricDataResult, queryCount)
+	var mu sync.Mutex
+	for i := 0; i < queryCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("q%d", i)
+			ch := batcher.Add(id, testQuery(), now.Add(-time.Hour), now)
+			mu.Lock()
+			results[i] = ch
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	batcher.Flush()
+
+	for _, ch := range results {
+		<-ch
+	}
+
+	for _, call := range client.calls {
+		if len(call.MetricDataQueries) > maxQueriesPerBatch {
+			t.Fatalf("GetMetricData call carried %d queries, exceeding the %d CloudWatch limit", len(call.MetricDataQueries), maxQueriesPerBatch)
+		}
+	}
+}
+
+// BenchmarkMetricQueryBatcher measures the cost of batching hundreds of queued
+// CloudWatch queries into GetMetricData calls, versus one call per query.
+func BenchmarkMetricQueryBatcher(b *testing.B) {
+	client := &benchMetricDataClient{}
+	now := time.Now()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batcher := aws.NewMetricQueryBatcher(client)
+
+		channels := make([]<-chan aws.MetricDataResult, 500)
+		for q := 0; q < 500; q++ {
+			id := fmt.Sprintf("q%d", q)
+			channels[q] = batcher.Add(id, &cloudwatch.MetricDataQuery{
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  awsClient.String("AWS/ApplicationELB"),
+						MetricName: awsClient.String("RequestCount"),
+					},
+					Period: awsClient.Int64(300),
+					Stat:   awsClient.String("Average"),
+				},
+			}, now.Add(-time.Hour), now)
+		}
+
+		batcher.Flush()
+
+		for _, ch := range channels {
+			<-ch
+		}
+	}
+}
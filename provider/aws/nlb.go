@@ -0,0 +1,251 @@
+package aws
+
+import (
+	"encoding/json"
+	"finala/config"
+	"finala/expression"
+	"finala/storage"
+	"finala/structs"
+	"regexp"
+	"time"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	log "github.com/sirupsen/logrus"
+)
+
+// NLBManager describe AWS Network Load Balancer struct
+type NLBManager struct {
+	client           ELBV2ClientDescreptor
+	storage          storage.Storage
+	cloudWatchCLient *CloudwatchManager
+	pricingClient    *PricingManager
+	metrics          []config.MetricConfig
+	region           string
+
+	namespace          string
+	servicePricingCode string
+
+	// tagFilter is applied to every detected resource; see TagFilter.
+	tagFilter *TagFilter
+}
+
+// DetectedNLB define the detected AWS Network Load Balancer instances
+type DetectedNLB struct {
+	Metric string
+	Region string
+	structs.BaseDetectedRaw
+}
+
+// TableName will set the table name to storage interface
+func (DetectedNLB) TableName() string {
+	return "aws_nlb"
+}
+
+// NewNLBManager implements AWS GO SDK
+func NewNLBManager(client ELBV2ClientDescreptor, st storage.Storage, cloudWatchCLient *CloudwatchManager, pricing *PricingManager, metrics []config.MetricConfig, region string, tagFilter *TagFilter) *NLBManager {
+
+	st.AutoMigrate(&DetectedNLB{})
+
+	return &NLBManager{
+		client:           client,
+		storage:          st,
+		cloudWatchCLient: cloudWatchCLient,
+		metrics:          metrics,
+		pricingClient:    pricing,
+		region:           region,
+		tagFilter:        tagFilter,
+
+		namespace:          "AWS/NetworkELB",
+		servicePricingCode: "AmazonEC2",
+	}
+}
+
+// Detect checks whether a Network Load Balancer instance is under utilization
+func (r *NLBManager) Detect() ([]DetectedNLB, error) {
+	log.Info("Analyze NLB")
+	detectedNLB := []DetectedNLB{}
+
+	instances, err := r.DescribeLoadbalancers(nil, nil)
+	if err != nil {
+		return detectedNLB, err
+	}
+
+	now := time.Now()
+
+	for _, instance := range instances {
+
+		if instance.Type != nil && *instance.Type != elbv2.LoadBalancerTypeEnumNetwork {
+			continue
+		}
+
+		log.WithField("name", *instance.LoadBalancerName).Info("check NLB")
+
+		var price float64
+		if r.pricingClient != nil {
+			price, _ = r.pricingClient.GetPrice(r.GetPricingFilterInput(), "")
+		}
+
+		for _, metric := range r.metrics {
+
+			log.WithFields(log.Fields{
+				"name":        *instance.LoadBalancerName,
+				"metric_name": metric.Description,
+			}).Debug("check metric")
+
+			period := int64(metric.Period.Seconds())
+
+			metricEndTime := now.Add(time.Duration(-metric.StartTime))
+
+			regx, _ := regexp.Compile(".*loadbalancer/")
+
+			nlbName := regx.ReplaceAllString(*instance.LoadBalancerArn, "")
+
+			metricInput := cloudwatch.GetMetricStatisticsInput{
+				Namespace:  &r.namespace,
+				MetricName: &metric.Description,
+				Period:     &period,
+				StartTime:  &metricEndTime,
+				EndTime:    &now,
+				Dimensions: []*cloudwatch.Dimension{
+					&cloudwatch.Dimension{
+						Name:  awsClient.String("LoadBalancer"),
+						Value: &nlbName,
+					},
+				},
+			}
+
+			metricResponse, err := r.cloudWatchCLient.GetMetric(&metricInput, metric)
+
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"name":        *instance.LoadBalancerName,
+					"metric_name": metric.Description,
+				}).Error("Could not get cloudwatch metric data")
+				continue
+			}
+
+			instanceCreateTime := *instance.CreatedTime
+			durationRunningTime := now.Sub(instanceCreateTime)
+			totalPrice := price * durationRunningTime.Hours()
+
+			expression, err := expression.BoolExpression(metricResponse, metric.Constraint.Value, metric.Constraint.Operator)
+			if err != nil {
+				continue
+			}
+
+			if expression {
+
+				decodedTags := []byte{}
+				tagPairs := map[string]string{}
+				tags, err := r.client.DescribeTags(&elbv2.DescribeTagsInput{
+					ResourceArns: []*string{instance.LoadBalancerArn},
+				})
+				if err == nil {
+					decodedTags, err = json.Marshal(&tags.TagDescriptions)
+					for _, description := range tags.TagDescriptions {
+						for _, tag := range description.Tags {
+							tagPairs[awsClient.StringValue(tag.Key)] = awsClient.StringValue(tag.Value)
+						}
+					}
+				}
+
+				if !r.tagFilter.allows(tagPairs) {
+					continue
+				}
+
+				log.WithFields(log.Fields{
+					"metric_name":         metric.Description,
+					"Constraint_operator": metric.Constraint.Operator,
+					"Constraint_Value":    metric.Constraint.Value,
+					"metric_response":     metricResponse,
+					"name":                *instance.LoadBalancerName,
+					"region":              r.region,
+				}).Info("LoadBalancer detected as unutilized resource")
+
+				nlb := DetectedNLB{
+					Region: r.region,
+					Metric: metric.Description,
+					BaseDetectedRaw: structs.BaseDetectedRaw{
+						ResourceID:      *instance.LoadBalancerName,
+						LaunchTime:      *instance.CreatedTime,
+						PricePerHour:    price,
+						PricePerMonth:   price * 720,
+						TotalSpendPrice: totalPrice,
+						Tags:            string(decodedTags),
+						CostGroup:       r.tagFilter.costGroup(tagPairs),
+					},
+				}
+				detectedNLB = append(detectedNLB, nlb)
+				r.storage.Create(&nlb)
+
+			}
+
+		}
+	}
+
+	return detectedNLB, nil
+
+}
+
+// GetPricingFilterInput prepare document nlb pricing filter
+func (r *NLBManager) GetPricingFilterInput() *pricing.GetProductsInput {
+
+	return &pricing.GetProductsInput{
+		ServiceCode: &r.servicePricingCode,
+		Filters: []*pricing.Filter{
+
+			&pricing.Filter{
+				Type:  awsClient.String("TERM_MATCH"),
+				Field: awsClient.String("usagetype"),
+				Value: awsClient.String("LoadBalancerUsage"),
+			},
+			&pricing.Filter{
+				Type:  awsClient.String("TERM_MATCH"),
+				Field: awsClient.String("productFamily"),
+				Value: awsClient.String("Load Balancer-Network"),
+			},
+			&pricing.Filter{
+				Type:  awsClient.String("TERM_MATCH"),
+				Field: awsClient.String("TermType"),
+				Value: awsClient.String("OnDemand"),
+			},
+
+			&pricing.Filter{
+				Type:  awsClient.String("TERM_MATCH"),
+				Field: awsClient.String("group"),
+				Value: awsClient.String("ELB:Balancer"),
+			},
+		},
+	}
+}
+
+// DescribeLoadbalancers return list of network load balancers
+func (r *NLBManager) DescribeLoadbalancers(marker *string, loadbalancers []*elbv2.LoadBalancer) ([]*elbv2.LoadBalancer, error) {
+
+	input := &elbv2.DescribeLoadBalancersInput{
+		Marker: marker,
+	}
+
+	resp, err := r.client.DescribeLoadBalancers(input)
+	if err != nil {
+		log.WithField("error", err).Error("could not describe nlb instances")
+		return nil, err
+	}
+
+	if loadbalancers == nil {
+		loadbalancers = []*elbv2.LoadBalancer{}
+	}
+
+	for _, lb := range resp.LoadBalancers {
+		loadbalancers = append(loadbalancers, lb)
+	}
+
+	if resp.NextMarker != nil {
+		return r.DescribeLoadbalancers(resp.NextMarker, loadbalancers)
+	}
+
+	return loadbalancers, nil
+}
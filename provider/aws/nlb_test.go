@@ -0,0 +1,129 @@
+package aws_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"finala/provider/aws"
+	"finala/testutils"
+
+	awsClient "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	log "github.com/sirupsen/logrus"
+)
+
+type mockNLBClient struct {
+	loadBalancers []*elbv2.LoadBalancer
+}
+
+func (m *mockNLBClient) DescribeLoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	return &elbv2.DescribeLoadBalancersOutput{LoadBalancers: m.loadBalancers}, nil
+}
+
+func (m *mockNLBClient) DescribeTags(input *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error) {
+	return &elbv2.DescribeTagsOutput{}, nil
+}
+
+func (m *mockNLBClient) DescribeListeners(input *elbv2.DescribeListenersInput) (*elbv2.DescribeListenersOutput, error) {
+	return &elbv2.DescribeListenersOutput{}, nil
+}
+
+func (m *mockNLBClient) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	return &elbv2.DescribeTargetGroupsOutput{}, nil
+}
+
+func (m *mockNLBClient) DescribeTargetHealth(input *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	return &elbv2.DescribeTargetHealthOutput{}, nil
+}
+
+func TestNLBManagerDescribeLoadbalancers(t *testing.T) {
+	client := &mockNLBClient{
+		loadBalancers: []*elbv2.LoadBalancer{
+			{LoadBalancerName: awsClient.String("nlb-0"), Type: awsClient.String(elbv2.LoadBalancerTypeEnumNetwork)},
+			{LoadBalancerName: awsClient.String("alb-0"), Type: awsClient.String(elbv2.LoadBalancerTypeEnumApplication)},
+		},
+	}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewNLBManager(client, mockStorage, nil, nil, nil, "us-east-1", nil)
+
+	loadbalancers, err := manager.DescribeLoadbalancers(nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(loadbalancers) != 2 {
+		t.Fatalf("unexpected load balancer count, got %d expected %d", len(loadbalancers), 2)
+	}
+}
+
+func TestNLBManagerDetectFiltersNonNetworkType(t *testing.T) {
+	client := &mockNLBClient{
+		loadBalancers: []*elbv2.LoadBalancer{
+			{LoadBalancerName: awsClient.String("nlb-0"), Type: awsClient.String(elbv2.LoadBalancerTypeEnumNetwork)},
+			{LoadBalancerName: awsClient.String("alb-0"), Type: awsClient.String(elbv2.LoadBalancerTypeEnumApplication)},
+		},
+	}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewNLBManager(client, mockStorage, nil, nil, nil, "us-east-1", nil)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	detected, err := manager.Detect()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(detected) != 0 {
+		t.Fatalf("expected no detections with no configured metrics, got %d", len(detected))
+	}
+
+	output := logs.String()
+	if !strings.Contains(output, `name=nlb-0`) {
+		t.Fatalf("expected the network load balancer to reach the per-instance scan, logs: %s", output)
+	}
+	if strings.Contains(output, `name=alb-0`) {
+		t.Fatalf("expected the application load balancer to be filtered out before the per-instance scan, logs: %s", output)
+	}
+}
+
+func TestNLBManagerDetectWithNilPricingClient(t *testing.T) {
+	client := &mockNLBClient{
+		loadBalancers: []*elbv2.LoadBalancer{
+			{LoadBalancerName: awsClient.String("nlb-0"), Type: awsClient.String(elbv2.LoadBalancerTypeEnumNetwork)},
+		},
+	}
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewNLBManager(client, mockStorage, nil, nil, nil, "us-east-1", nil)
+
+	detected, err := manager.Detect()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(detected) != 0 {
+		t.Fatalf("expected no detections with no configured metrics, got %d", len(detected))
+	}
+}
+
+func TestNLBManagerGetPricingFilterInput(t *testing.T) {
+	mockStorage := testutils.NewMockStorage()
+	manager := aws.NewNLBManager(&mockNLBClient{}, mockStorage, nil, nil, nil, "us-east-1", nil)
+
+	filter := manager.GetPricingFilterInput()
+
+	found := false
+	for _, f := range filter.Filters {
+		if awsClient.StringValue(f.Field) == "productFamily" {
+			found = true
+			if awsClient.StringValue(f.Value) != "Load Balancer-Network" {
+				t.Fatalf("unexpected productFamily, got %s", awsClient.StringValue(f.Value))
+			}
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected NLB pricing filter to constrain productFamily")
+	}
+}
@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// requestCache memoizes responses for identical outbound requests (e.g. pricing
+// lookups, tag descriptions) made repeatedly while scanning a region. Entries are
+// keyed by a hash of the request input rather than a caller-supplied string, so two
+// different resources can never collide under the same cache key.
+type requestCache struct {
+	mu      sync.RWMutex
+	entries map[string]interface{}
+}
+
+// newRequestCache returns an empty, ready to use requestCache.
+func newRequestCache() *requestCache {
+	return &requestCache{
+		entries: map[string]interface{}{},
+	}
+}
+
+// requestCacheKey computes a stable content hash for an arbitrary request input.
+func requestCacheKey(input interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// get returns the cached value for key, if present.
+func (c *requestCache) get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+// set stores value under key.
+func (c *requestCache) set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = value
+}
+
+// reset discards every cached entry, so the next get for any key misses.
+func (c *requestCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]interface{}{}
+}
@@ -0,0 +1,60 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestRequestCacheGetSetReset verifies the cache returns what was stored under
+// a key, and that reset clears every entry so the next scan's lookups miss
+// instead of serving stale values from a prior Detect() call.
+func TestRequestCacheGetSetReset(t *testing.T) {
+	cache := newRequestCache()
+
+	key, err := requestCacheKey(struct{ ResourceArn string }{ResourceArn: "arn-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, ok := cache.get(key); ok {
+		t.Fatalf("expected miss on empty cache")
+	}
+
+	cache.set(key, "tagged-prod")
+
+	value, ok := cache.get(key)
+	if !ok || value.(string) != "tagged-prod" {
+		t.Fatalf("expected cached value %q, got %v (ok=%v)", "tagged-prod", value, ok)
+	}
+
+	cache.reset()
+
+	if _, ok := cache.get(key); ok {
+		t.Fatalf("expected miss after reset, cache still served stale value")
+	}
+}
+
+// BenchmarkRequestCache measures the memoization cache used to avoid repeating
+// identical pricing/tag requests across hundreds of load balancers.
+func BenchmarkRequestCache(b *testing.B) {
+	cache := newRequestCache()
+
+	type input struct {
+		ResourceArns []string
+	}
+
+	keys := make([]string, 100)
+	for i := range keys {
+		key, err := requestCacheKey(input{ResourceArns: []string{fmt.Sprintf("arn-%d", i)}})
+		if err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+		keys[i] = key
+		cache.set(key, i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.get(keys[i%len(keys)])
+	}
+}
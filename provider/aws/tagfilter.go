@@ -0,0 +1,52 @@
+package aws
+
+// TagFilter controls which resources a manager persists, and how wasted spend
+// is grouped for cost-allocation reporting, based on a resource's AWS tags.
+//
+// This is infrastructure only: callers build a *TagFilter directly in Go and
+// pass it to NewELBManager/NewELBV2Manager/NewNLBManager. Parsing an
+// operator-facing config value (e.g. a YAML list like "Environment=prod" /
+// "!Team=platform") into a TagFilter is follow-up work, tracked separately,
+// and isn't wired up here.
+type TagFilter struct {
+	// Include, when non-empty, keeps only resources carrying every one of these
+	// tag key/value pairs.
+	Include map[string]string
+	// Exclude drops any resource carrying one of these tag key/value pairs,
+	// checked after Include.
+	Exclude map[string]string
+	// CostAllocationTagKey, when present on a resource, is copied into the
+	// detected resource's CostGroup field so reports can aggregate wasted
+	// spend by team/environment.
+	CostAllocationTagKey string
+}
+
+// allows reports whether a resource carrying tags passes this filter. A nil
+// TagFilter allows everything.
+func (f *TagFilter) allows(tags map[string]string) bool {
+	if f == nil {
+		return true
+	}
+
+	for key, value := range f.Include {
+		if tags[key] != value {
+			return false
+		}
+	}
+
+	for key, value := range f.Exclude {
+		if tags[key] == value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// costGroup returns the value of the configured cost-allocation tag, if any.
+func (f *TagFilter) costGroup(tags map[string]string) string {
+	if f == nil || f.CostAllocationTagKey == "" {
+		return ""
+	}
+	return tags[f.CostAllocationTagKey]
+}
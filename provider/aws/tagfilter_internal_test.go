@@ -0,0 +1,96 @@
+package aws
+
+import "testing"
+
+func TestTagFilterAllowsNilFilterAllowsEverything(t *testing.T) {
+	var filter *TagFilter
+	if !filter.allows(map[string]string{"Environment": "dev"}) {
+		t.Fatalf("a nil TagFilter should allow every resource")
+	}
+}
+
+func TestTagFilterAllowsIncludeExcludePrecedence(t *testing.T) {
+	cases := []struct {
+		name   string
+		filter *TagFilter
+		tags   map[string]string
+		want   bool
+	}{
+		{
+			name:   "include satisfied",
+			filter: &TagFilter{Include: map[string]string{"Environment": "prod"}},
+			tags:   map[string]string{"Environment": "prod"},
+			want:   true,
+		},
+		{
+			name:   "include not satisfied",
+			filter: &TagFilter{Include: map[string]string{"Environment": "prod"}},
+			tags:   map[string]string{"Environment": "dev"},
+			want:   false,
+		},
+		{
+			name:   "include missing tag",
+			filter: &TagFilter{Include: map[string]string{"Environment": "prod"}},
+			tags:   map[string]string{},
+			want:   false,
+		},
+		{
+			name:   "exclude matched",
+			filter: &TagFilter{Exclude: map[string]string{"Team": "sandbox"}},
+			tags:   map[string]string{"Team": "sandbox"},
+			want:   false,
+		},
+		{
+			name:   "exclude not matched",
+			filter: &TagFilter{Exclude: map[string]string{"Team": "sandbox"}},
+			tags:   map[string]string{"Team": "platform"},
+			want:   true,
+		},
+		{
+			name: "exclude takes precedence over include",
+			filter: &TagFilter{
+				Include: map[string]string{"Environment": "prod"},
+				Exclude: map[string]string{"Team": "sandbox"},
+			},
+			tags: map[string]string{"Environment": "prod", "Team": "sandbox"},
+			want: false,
+		},
+		{
+			name: "include and exclude both satisfied to allow",
+			filter: &TagFilter{
+				Include: map[string]string{"Environment": "prod"},
+				Exclude: map[string]string{"Team": "sandbox"},
+			},
+			tags: map[string]string{"Environment": "prod", "Team": "platform"},
+			want: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.allows(tc.tags); got != tc.want {
+				t.Fatalf("allows() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTagFilterCostGroup(t *testing.T) {
+	var nilFilter *TagFilter
+	if got := nilFilter.costGroup(map[string]string{"CostCenter": "1234"}); got != "" {
+		t.Fatalf("a nil TagFilter should report no cost group, got %q", got)
+	}
+
+	noKeyFilter := &TagFilter{}
+	if got := noKeyFilter.costGroup(map[string]string{"CostCenter": "1234"}); got != "" {
+		t.Fatalf("a TagFilter without a CostAllocationTagKey should report no cost group, got %q", got)
+	}
+
+	filter := &TagFilter{CostAllocationTagKey: "CostCenter"}
+	if got := filter.costGroup(map[string]string{"CostCenter": "1234"}); got != "1234" {
+		t.Fatalf("expected cost group %q, got %q", "1234", got)
+	}
+	if got := filter.costGroup(map[string]string{}); got != "" {
+		t.Fatalf("expected empty cost group when the tag is absent, got %q", got)
+	}
+}
@@ -0,0 +1,18 @@
+package structs
+
+import "time"
+
+// BaseDetectedRaw holds the fields common to every detected wasted resource,
+// embedded by each provider's detected-resource struct.
+type BaseDetectedRaw struct {
+	ResourceID      string
+	LaunchTime      time.Time
+	PricePerHour    float64
+	PricePerMonth   float64
+	TotalSpendPrice float64
+	Tags            string
+	// CostGroup is the value of a configured cost-allocation tag, when the
+	// detector was configured with one, letting reports aggregate wasted
+	// spend by team/environment/whatever the tag represents.
+	CostGroup string
+}